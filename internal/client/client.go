@@ -1,28 +1,76 @@
 package client
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"math/rand"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
 )
 
+const (
+	defaultMaxRetries     = 3
+	defaultRetryWaitMin   = 1 * time.Second
+	defaultRetryWaitMax   = 30 * time.Second
+	defaultRequestTimeout = 30 * time.Second
+)
+
 type Config struct {
 	Address    string
 	Scheme     string
 	Stack      string
 	HttpClient *http.Client
+
+	// MaxRetries is the number of additional attempts made after a request
+	// fails with a retryable error, capping the total attempts at
+	// MaxRetries+1. RetryWaitMin and RetryWaitMax bound the full-jitter
+	// backoff applied between attempts, and RequestTimeout bounds each
+	// individual attempt.
+	MaxRetries     int
+	RetryWaitMin   time.Duration
+	RetryWaitMax   time.Duration
+	RequestTimeout time.Duration
+
+	// Token is sent as an "Authorization: Bearer <token>" header on every
+	// request. TokenFile, if set, is read once by NewClient and takes
+	// precedence over Token.
+	Token     string
+	TokenFile string
+
+	// CACert/CACertFile, ClientCert/ClientCertFile, and
+	// ClientKey/ClientKeyFile configure mTLS. The *File variants are read
+	// once by NewClient and take precedence over their inline counterparts.
+	// Insecure disables TLS certificate verification and should only be
+	// used against dev servers.
+	CACert         string
+	CACertFile     string
+	ClientCert     string
+	ClientCertFile string
+	ClientKey      string
+	ClientKeyFile  string
+	Insecure       bool
 }
 
 func DefaultConfig() *Config {
 	config := &Config{
-		Scheme:     "https",
-		HttpClient: cleanhttp.DefaultPooledClient(),
+		Scheme:         "https",
+		HttpClient:     cleanhttp.DefaultPooledClient(),
+		MaxRetries:     defaultMaxRetries,
+		RetryWaitMin:   defaultRetryWaitMin,
+		RetryWaitMax:   defaultRetryWaitMax,
+		RequestTimeout: defaultRequestTimeout,
 	}
 
 	if addr := os.Getenv("watson_ADDRESS"); addr != "" {
@@ -34,6 +82,55 @@ func DefaultConfig() *Config {
 	if stack := os.Getenv("watson_STACK"); stack != "" {
 		config.Stack = stack
 	}
+	if v := os.Getenv("watson_MAX_RETRIES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.MaxRetries = n
+		}
+	}
+	if v := os.Getenv("watson_RETRY_WAIT_MIN"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.RetryWaitMin = d
+		}
+	}
+	if v := os.Getenv("watson_RETRY_WAIT_MAX"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.RetryWaitMax = d
+		}
+	}
+	if v := os.Getenv("watson_REQUEST_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.RequestTimeout = d
+		}
+	}
+	if v := os.Getenv("watson_TOKEN"); v != "" {
+		config.Token = v
+	}
+	if v := os.Getenv("watson_TOKEN_FILE"); v != "" {
+		config.TokenFile = v
+	}
+	if v := os.Getenv("watson_CA_CERT"); v != "" {
+		config.CACert = v
+	}
+	if v := os.Getenv("watson_CA_CERT_FILE"); v != "" {
+		config.CACertFile = v
+	}
+	if v := os.Getenv("watson_CLIENT_CERT"); v != "" {
+		config.ClientCert = v
+	}
+	if v := os.Getenv("watson_CLIENT_CERT_FILE"); v != "" {
+		config.ClientCertFile = v
+	}
+	if v := os.Getenv("watson_CLIENT_KEY"); v != "" {
+		config.ClientKey = v
+	}
+	if v := os.Getenv("watson_CLIENT_KEY_FILE"); v != "" {
+		config.ClientKeyFile = v
+	}
+	if v := os.Getenv("watson_INSECURE"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			config.Insecure = b
+		}
+	}
 
 	return config
 }
@@ -41,10 +138,23 @@ func DefaultConfig() *Config {
 type Client struct {
 	config  Config
 	headers http.Header
+
+	outputsCacheMu sync.Mutex
+	outputsCache   map[string]*Outputs
 }
 
+// NewClient builds a Client from config. config should normally be built
+// from DefaultConfig and then have individual fields overridden: unlike the
+// string fields below, MaxRetries, RetryWaitMin, RetryWaitMax,
+// RequestTimeout, and Insecure are not re-defaulted here, since their zero
+// values (no retries, no wait, no timeout, secure) are meaningful explicit
+// choices and not just "unset". In particular, re-defaulting Insecure from
+// a freshly read DefaultConfig would let a watson_INSECURE environment
+// variable silently override an explicit insecure = false in Terraform.
 func NewClient(config *Config) (*Client, error) {
 	defConfig := DefaultConfig()
+	httpClientProvided := config.HttpClient != nil
+
 	if config.Address == "" {
 		config.Address = defConfig.Address
 	}
@@ -57,6 +167,59 @@ func NewClient(config *Config) (*Client, error) {
 	if config.HttpClient == nil {
 		config.HttpClient = defConfig.HttpClient
 	}
+	if config.Token == "" {
+		config.Token = defConfig.Token
+	}
+	if config.TokenFile == "" {
+		config.TokenFile = defConfig.TokenFile
+	}
+	if config.CACert == "" {
+		config.CACert = defConfig.CACert
+	}
+	if config.CACertFile == "" {
+		config.CACertFile = defConfig.CACertFile
+	}
+	if config.ClientCert == "" {
+		config.ClientCert = defConfig.ClientCert
+	}
+	if config.ClientCertFile == "" {
+		config.ClientCertFile = defConfig.ClientCertFile
+	}
+	if config.ClientKey == "" {
+		config.ClientKey = defConfig.ClientKey
+	}
+	if config.ClientKeyFile == "" {
+		config.ClientKeyFile = defConfig.ClientKeyFile
+	}
+
+	if config.TokenFile != "" {
+		b, err := os.ReadFile(config.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token_file: %w", err)
+		}
+		config.Token = strings.TrimSpace(string(b))
+	}
+	if config.CACertFile != "" {
+		b, err := os.ReadFile(config.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_cert_file: %w", err)
+		}
+		config.CACert = string(b)
+	}
+	if config.ClientCertFile != "" {
+		b, err := os.ReadFile(config.ClientCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client_cert_file: %w", err)
+		}
+		config.ClientCert = string(b)
+	}
+	if config.ClientKeyFile != "" {
+		b, err := os.ReadFile(config.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client_key_file: %w", err)
+		}
+		config.ClientKey = string(b)
+	}
 
 	parts := strings.SplitN(config.Address, "://", 2)
 	if len(parts) == 2 {
@@ -71,28 +234,169 @@ func NewClient(config *Config) (*Client, error) {
 		config.Address = parts[1]
 	}
 
+	if !httpClientProvided && (config.Insecure || config.CACert != "" || config.ClientCert != "" || config.ClientKey != "") {
+		tlsConfig, err := buildTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+
+		transport := cleanhttp.DefaultPooledTransport()
+		transport.TLSClientConfig = tlsConfig
+		config.HttpClient = &http.Client{Transport: transport}
+	}
+
 	headers := make(http.Header)
 	if config.Stack != "" {
 		headers.Set("x-watson-stack", config.Stack)
 	}
+	if config.Token != "" {
+		headers.Set("Authorization", "Bearer "+config.Token)
+	}
 
 	return &Client{config: *config, headers: headers}, nil
 }
 
-// doRequest runs a request with our client
-func (c *Client) doRequest(path string) (*http.Response, error) {
-	req, err := http.NewRequest("GET", path, nil)
-	if err != nil {
-		return nil, err
+// buildTLSConfig builds the *tls.Config used when the provider is
+// configured with a CA certificate, a client certificate/key pair, or
+// insecure mode.
+func buildTLSConfig(config *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: config.Insecure}
+
+	if config.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(config.CACert)) {
+			return nil, fmt.Errorf("failed to parse ca_cert as PEM")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.ClientCert != "" || config.ClientKey != "" {
+		if config.ClientCert == "" || config.ClientKey == "" {
+			return nil, fmt.Errorf("client_cert and client_key must be set together")
+		}
+
+		cert, err := tls.X509KeyPair([]byte(config.ClientCert), []byte(config.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
-	req.URL.Scheme = c.config.Scheme
-	req.URL.Host = c.config.Address
-	req.Header = c.headers
-	return c.config.HttpClient.Do(req)
+
+	return tlsConfig, nil
+}
+
+// doRequest runs a request with our client, retrying connection errors,
+// 429s, and 5xxs (except 501, which is never transient) up to
+// config.MaxRetries times with a full-jitter backoff between attempts.
+// Each attempt, including retries, is bounded by config.RequestTimeout, and
+// the whole operation aborts as soon as ctx is done.
+func (c *Client) doRequest(ctx context.Context, method, path string) (*http.Response, error) {
+	return c.doRequestBody(ctx, method, path, nil)
+}
+
+// doRequestBody is doRequest with an optional JSON request body. body is
+// re-read from scratch on every attempt since earlier attempts may have
+// consumed it.
+func (c *Client) doRequestBody(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	var lastErr error
+
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, c.config.RequestTimeout)
+
+		var bodyReader io.Reader
+		if body != nil {
+			bodyReader = bytes.NewReader(body)
+		}
+
+		req, err := http.NewRequestWithContext(attemptCtx, method, path, bodyReader)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		req.URL.Scheme = c.config.Scheme
+		req.URL.Host = c.config.Address
+		req.Header = c.headers.Clone()
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.config.HttpClient.Do(req)
+		if err == nil && !shouldRetryStatus(resp.StatusCode) {
+			cancel()
+			return resp, nil
+		}
+
+		if err != nil {
+			lastErr = err
+		} else {
+			lastErr = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		if attempt >= c.config.MaxRetries {
+			cancel()
+			return nil, lastErr
+		}
+
+		wait := c.retryWait(attempt, resp)
+		if resp != nil {
+			closeResponseBody(resp)
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// shouldRetryStatus reports whether an HTTP status code is worth retrying:
+// rate limiting and server errors, but not 501 Not Implemented, which will
+// never succeed on retry.
+func shouldRetryStatus(code int) bool {
+	if code == http.StatusTooManyRequests {
+		return true
+	}
+	return code >= 500 && code != http.StatusNotImplemented
+}
+
+// retryWait returns how long to sleep before the next attempt, honoring a
+// Retry-After header if the server sent one, otherwise applying full jitter
+// between RetryWaitMin and an exponentially growing cap.
+func (c *Client) retryWait(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if ra := resp.Header.Get("Retry-After"); ra != "" {
+			if secs, err := strconv.Atoi(ra); err == nil {
+				return time.Duration(secs) * time.Second
+			}
+			if t, err := http.ParseTime(ra); err == nil {
+				if d := time.Until(t); d > 0 {
+					return d
+				}
+			}
+		}
+	}
+
+	min := c.config.RetryWaitMin
+	max := c.config.RetryWaitMax
+	if max <= min {
+		return min
+	}
+
+	capped := time.Duration(float64(min) * math.Pow(2, float64(attempt)))
+	if capped <= 0 || capped > max {
+		capped = max
+	}
+
+	return min + time.Duration(rand.Int63n(int64(capped-min+1)))
 }
 
 type Output struct {
+	// Value holds the raw JSON value of the output: a string, bool,
+	// json.Number, []interface{}, map[string]interface{}, or nil.
 	Value      interface{}
+	Sensitive  bool
 	Deprecated string
 	Warning    string
 }
@@ -107,11 +411,11 @@ func validateStackName(stack string) error {
 	return nil
 }
 
-func (c *Client) GetOutputs(stack string) (*Outputs, error) {
+func (c *Client) GetOutputs(ctx context.Context, stack string) (*Outputs, error) {
 	if err := validateStackName(stack); err != nil {
 		return nil, err
 	}
-	resp, err := c.doRequest(fmt.Sprintf("/v1/projects/%s/outputs/", stack))
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/v1/projects/%s/outputs/", stack))
 	if err != nil {
 		return nil, err
 	}
@@ -127,6 +431,7 @@ func (c *Client) GetOutputs(stack string) (*Outputs, error) {
 
 	var outputs Outputs
 	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
 	if err := dec.Decode(&outputs); err != nil {
 		return nil, err
 	}
@@ -134,6 +439,33 @@ func (c *Client) GetOutputs(stack string) (*Outputs, error) {
 	return &outputs, nil
 }
 
+// GetOutputsCached is GetOutputs, memoized for the lifetime of this Client.
+// A Client is created once per Terraform run (see provider.Configure), so
+// this lets repeated provider::watson::output() calls for the same stack
+// within one run share a single HTTP round trip instead of one each.
+// Callers that need a fresh read on every call (the data source, the
+// ephemeral resource) should keep calling GetOutputs directly.
+func (c *Client) GetOutputsCached(ctx context.Context, stack string) (*Outputs, error) {
+	c.outputsCacheMu.Lock()
+	defer c.outputsCacheMu.Unlock()
+
+	if outputs, ok := c.outputsCache[stack]; ok {
+		return outputs, nil
+	}
+
+	outputs, err := c.GetOutputs(ctx, stack)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.outputsCache == nil {
+		c.outputsCache = map[string]*Outputs{}
+	}
+	c.outputsCache[stack] = outputs
+
+	return outputs, nil
+}
+
 type Stack struct {
 	Id     string `json:"id"`
 	Name   string `json:"name"`
@@ -145,11 +477,11 @@ type Stack struct {
 	} `json:"used_by"`
 }
 
-func (c *Client) GetStack(stack string) (*Stack, error) {
+func (c *Client) GetStack(ctx context.Context, stack string) (*Stack, error) {
 	if err := validateStackName(stack); err != nil {
 		return nil, err
 	}
-	resp, err := c.doRequest(fmt.Sprintf("/v1/projects/%s/", stack))
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/v1/projects/%s/", stack))
 	if err != nil {
 		return nil, err
 	}
@@ -172,6 +504,69 @@ func (c *Client) GetStack(stack string) (*Stack, error) {
 	return &s, nil
 }
 
+// StackOutputInput is the request payload for a single output of
+// CreateStack/UpdateStack.
+type StackOutputInput struct {
+	Value      interface{} `json:"value"`
+	Sensitive  bool        `json:"sensitive,omitempty"`
+	Deprecated string      `json:"deprecated,omitempty"`
+	Warning    string      `json:"warning,omitempty"`
+}
+
+type stackRequest struct {
+	Outputs map[string]StackOutputInput `json:"outputs"`
+}
+
+// CreateStack publishes a new stack, namespace/name, with the given
+// outputs.
+func (c *Client) CreateStack(ctx context.Context, namespace, name string, outputs map[string]StackOutputInput) (*Stack, error) {
+	return c.putOrPostStack(ctx, "POST", namespace, name, outputs)
+}
+
+// UpdateStack replaces the outputs of the existing stack namespace/name.
+func (c *Client) UpdateStack(ctx context.Context, namespace, name string, outputs map[string]StackOutputInput) (*Stack, error) {
+	return c.putOrPostStack(ctx, "PUT", namespace, name, outputs)
+}
+
+func (c *Client) putOrPostStack(ctx context.Context, method, namespace, name string, outputs map[string]StackOutputInput) (*Stack, error) {
+	body, err := json.Marshal(stackRequest{Outputs: outputs})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequestBody(ctx, method, fmt.Sprintf("/v1/projects/%s/%s/", namespace, name), body)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if resp.StatusCode != 200 && resp.StatusCode != 201 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var s Stack
+	dec := json.NewDecoder(resp.Body)
+	if err := dec.Decode(&s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// DeleteStack deletes the stack namespace/name.
+func (c *Client) DeleteStack(ctx context.Context, namespace, name string) error {
+	resp, err := c.doRequest(ctx, "DELETE", fmt.Sprintf("/v1/projects/%s/%s/", namespace, name))
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(resp)
+	switch code := resp.StatusCode; code {
+	case 200, 204, 404:
+		return nil
+	default:
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+}
+
 func closeResponseBody(resp *http.Response) error {
 	_, _ = io.Copy(io.Discard, resp.Body)
 	return resp.Body.Close()
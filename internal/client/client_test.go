@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewClient_TokenPrecedence(t *testing.T) {
+	t.Setenv("watson_TOKEN", "env-token")
+
+	config := DefaultConfig()
+	config.Address = "example.com"
+	c, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.headers.Get("Authorization"); got != "Bearer env-token" {
+		t.Fatalf("expected env var token to be used, got %q", got)
+	}
+
+	config = DefaultConfig()
+	config.Address = "example.com"
+	config.Token = "explicit-token"
+	c, err = NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.headers.Get("Authorization"); got != "Bearer explicit-token" {
+		t.Fatalf("expected explicit config to take precedence over the env var, got %q", got)
+	}
+}
+
+func TestNewClient_TokenFilePrecedence(t *testing.T) {
+	tokenFile := tempFile(t, "file-token\n")
+
+	config := DefaultConfig()
+	config.Address = "example.com"
+	config.Token = "inline-token"
+	config.TokenFile = tokenFile
+	c, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.headers.Get("Authorization"); got != "Bearer file-token" {
+		t.Fatalf("expected token_file to take precedence over token, got %q", got)
+	}
+}
+
+func TestNewClient_BadCACert(t *testing.T) {
+	config := DefaultConfig()
+	config.Address = "example.com"
+	config.CACert = "not a pem certificate"
+	_, err := NewClient(config)
+	if err == nil {
+		t.Fatal("expected an error for an invalid ca_cert")
+	}
+}
+
+func TestNewClient_BadClientCert(t *testing.T) {
+	config := DefaultConfig()
+	config.Address = "example.com"
+	config.ClientCert = "not a pem certificate"
+	config.ClientKey = "not a pem key"
+	_, err := NewClient(config)
+	if err == nil {
+		t.Fatal("expected an error for an invalid client_cert/client_key pair")
+	}
+}
+
+func TestNewClient_ClientCertRequiresClientKey(t *testing.T) {
+	config := DefaultConfig()
+	config.Address = "example.com"
+	config.ClientCert = "cert without a key"
+	_, err := NewClient(config)
+	if err == nil {
+		t.Fatal("expected an error when client_cert is set without client_key")
+	}
+}
+
+func TestShouldRetryStatus(t *testing.T) {
+	tests := []struct {
+		code int
+		want bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusNotFound, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+		{http.StatusNotImplemented, false},
+	}
+
+	for _, tt := range tests {
+		if got := shouldRetryStatus(tt.code); got != tt.want {
+			t.Errorf("shouldRetryStatus(%d) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestClient_RetryWait_BoundedByMinAndMax(t *testing.T) {
+	c := &Client{config: Config{RetryWaitMin: 10 * time.Millisecond, RetryWaitMax: 40 * time.Millisecond}}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		wait := c.retryWait(attempt, nil)
+		if wait < c.config.RetryWaitMin || wait > c.config.RetryWaitMax {
+			t.Fatalf("retryWait(%d, nil) = %s, want between %s and %s", attempt, wait, c.config.RetryWaitMin, c.config.RetryWaitMax)
+		}
+	}
+}
+
+func TestClient_RetryWait_HonorsRetryAfterHeader(t *testing.T) {
+	c := &Client{config: Config{RetryWaitMin: time.Second, RetryWaitMax: time.Minute}}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+	if got, want := c.retryWait(0, resp), 2*time.Second; got != want {
+		t.Fatalf("retryWait with Retry-After: 2 = %s, want %s", got, want)
+	}
+}
+
+func TestDoRequest_RetriesOnServerErrorThenSucceeds(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.Address = server.URL
+	config.MaxRetries = 3
+	config.RetryWaitMin = time.Millisecond
+	config.RetryWaitMax = 5 * time.Millisecond
+	config.RequestTimeout = time.Second
+	c, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.doRequest(context.Background(), "GET", "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeResponseBody(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected a 200 after retrying, got %d", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("expected 3 attempts (2 failures + 1 success), got %d", got)
+	}
+}
+
+func TestDoRequest_GivesUpAfterMaxRetries(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.Address = server.URL
+	config.MaxRetries = 2
+	config.RetryWaitMin = time.Millisecond
+	config.RetryWaitMax = 5 * time.Millisecond
+	config.RequestTimeout = time.Second
+	c, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.doRequest(context.Background(), "GET", "/"); err == nil {
+		t.Fatal("expected an error once retries are exhausted")
+	}
+	if got, want := atomic.LoadInt32(&attempts), int32(3); got != want {
+		t.Fatalf("expected %d attempts (1 initial + 2 retries), got %d", want, got)
+	}
+}
+
+func TestGetOutputsCached(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hostname": {"value": "localhost"}}`))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.Address = server.URL
+	c, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.GetOutputsCached(context.Background(), "backend/load-balancers"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetOutputsCached(context.Background(), "backend/load-balancers"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := atomic.LoadInt32(&requests), int32(1); got != want {
+		t.Fatalf("expected a repeated GetOutputsCached call for the same stack to be memoized, got %d requests, want %d", got, want)
+	}
+
+	if _, err := c.GetOutputsCached(context.Background(), "frontend/dev"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := atomic.LoadInt32(&requests), int32(2); got != want {
+		t.Fatalf("expected a different stack to issue its own request, got %d requests, want %d", got, want)
+	}
+}
+
+func tempFile(t *testing.T, contents string) string {
+	t.Helper()
+
+	f, err := os.CreateTemp(t.TempDir(), "watson-client-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(contents); err != nil {
+		t.Fatal(err)
+	}
+
+	return f.Name()
+}
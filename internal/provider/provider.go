@@ -2,19 +2,27 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"time"
 
+	"github.com/Lenstra/watson/internal/client"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/remilapeyre/watson/internal/client"
 )
 
 // Ensure watsonProvider satisfies various provider interfaces.
-var _ provider.Provider = &watsonProvider{}
+var (
+	_ provider.Provider                       = &watsonProvider{}
+	_ provider.ProviderWithFunctions          = &watsonProvider{}
+	_ provider.ProviderWithEphemeralResources = &watsonProvider{}
+)
 
 // watsonProvider defines the provider implementation.
 type watsonProvider struct {
@@ -26,9 +34,22 @@ type watsonProvider struct {
 
 // watsonProviderModel describes the provider data model.
 type watsonProviderModel struct {
-	Address types.String `tfsdk:"address"`
-	Scheme  types.String `tfsdk:"scheme"`
-	Stack   types.String `tfsdk:"stack"`
+	Address        types.String `tfsdk:"address"`
+	Scheme         types.String `tfsdk:"scheme"`
+	Stack          types.String `tfsdk:"stack"`
+	MaxRetries     types.Int64  `tfsdk:"max_retries"`
+	RetryWaitMin   types.String `tfsdk:"retry_wait_min"`
+	RetryWaitMax   types.String `tfsdk:"retry_wait_max"`
+	RequestTimeout types.String `tfsdk:"request_timeout"`
+	Token          types.String `tfsdk:"token"`
+	TokenFile      types.String `tfsdk:"token_file"`
+	CACert         types.String `tfsdk:"ca_cert"`
+	CACertFile     types.String `tfsdk:"ca_cert_file"`
+	ClientCert     types.String `tfsdk:"client_cert"`
+	ClientCertFile types.String `tfsdk:"client_cert_file"`
+	ClientKey      types.String `tfsdk:"client_key"`
+	ClientKeyFile  types.String `tfsdk:"client_key_file"`
+	Insecure       types.Bool   `tfsdk:"insecure"`
 }
 
 func (p *watsonProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -50,6 +71,74 @@ func (p *watsonProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 			"stack": schema.StringAttribute{
 				Optional: true,
 			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "Number of additional attempts made after a request fails with a retryable " +
+					"error. Defaults to 3, or the watson_MAX_RETRIES environment variable.",
+				Optional: true,
+			},
+			"retry_wait_min": schema.StringAttribute{
+				MarkdownDescription: "Minimum wait, as a Go duration string (e.g. \"1s\"), between retries. " +
+					"Defaults to 1s, or the watson_RETRY_WAIT_MIN environment variable.",
+				Optional: true,
+			},
+			"retry_wait_max": schema.StringAttribute{
+				MarkdownDescription: "Maximum wait, as a Go duration string (e.g. \"30s\"), between retries. " +
+					"Defaults to 30s, or the watson_RETRY_WAIT_MAX environment variable.",
+				Optional: true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "Timeout, as a Go duration string (e.g. \"30s\"), applied to each individual " +
+					"attempt. Defaults to 30s, or the watson_REQUEST_TIMEOUT environment variable.",
+				Optional: true,
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "Bearer token sent as the `Authorization` header on every request. " +
+					"Can also be set with the watson_TOKEN environment variable.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"token_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file containing the bearer token, read once at provider " +
+					"configuration. Takes precedence over `token`. Can also be set with the watson_TOKEN_FILE " +
+					"environment variable.",
+				Optional: true,
+			},
+			"ca_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded CA certificate used to verify the watson server's certificate. " +
+					"Can also be set with the watson_CA_CERT environment variable.",
+				Optional: true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM-encoded CA certificate file. Takes precedence over `ca_cert`. " +
+					"Can also be set with the watson_CA_CERT_FILE environment variable.",
+				Optional: true,
+			},
+			"client_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded client certificate used for mTLS. Requires `client_key`. " +
+					"Can also be set with the watson_CLIENT_CERT environment variable.",
+				Optional: true,
+			},
+			"client_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM-encoded client certificate file. Takes precedence over " +
+					"`client_cert`. Can also be set with the watson_CLIENT_CERT_FILE environment variable.",
+				Optional: true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded client private key used for mTLS. Requires `client_cert`. " +
+					"Can also be set with the watson_CLIENT_KEY environment variable.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"client_key_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM-encoded client private key file. Takes precedence over " +
+					"`client_key`. Can also be set with the watson_CLIENT_KEY_FILE environment variable.",
+				Optional: true,
+			},
+			"insecure": schema.BoolAttribute{
+				MarkdownDescription: "Skip TLS certificate verification. Only use against dev servers. " +
+					"Can also be set with the watson_INSECURE environment variable.",
+				Optional: true,
+			},
 		},
 	}
 }
@@ -149,6 +238,52 @@ func (p *watsonProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		conf.Stack = stack
 	}
 
+	if !config.MaxRetries.IsNull() {
+		conf.MaxRetries = int(config.MaxRetries.ValueInt64())
+	}
+
+	if d, ok := parseDurationAttribute(config.RetryWaitMin, path.Root("retry_wait_min"), resp); ok {
+		conf.RetryWaitMin = d
+	}
+	if d, ok := parseDurationAttribute(config.RetryWaitMax, path.Root("retry_wait_max"), resp); ok {
+		conf.RetryWaitMax = d
+	}
+	if d, ok := parseDurationAttribute(config.RequestTimeout, path.Root("request_timeout"), resp); ok {
+		conf.RequestTimeout = d
+	}
+
+	if !config.Token.IsNull() {
+		conf.Token = config.Token.ValueString()
+	}
+	if !config.TokenFile.IsNull() {
+		conf.TokenFile = config.TokenFile.ValueString()
+	}
+	if !config.CACert.IsNull() {
+		conf.CACert = config.CACert.ValueString()
+	}
+	if !config.CACertFile.IsNull() {
+		conf.CACertFile = config.CACertFile.ValueString()
+	}
+	if !config.ClientCert.IsNull() {
+		conf.ClientCert = config.ClientCert.ValueString()
+	}
+	if !config.ClientCertFile.IsNull() {
+		conf.ClientCertFile = config.ClientCertFile.ValueString()
+	}
+	if !config.ClientKey.IsNull() {
+		conf.ClientKey = config.ClientKey.ValueString()
+	}
+	if !config.ClientKeyFile.IsNull() {
+		conf.ClientKeyFile = config.ClientKeyFile.ValueString()
+	}
+	if !config.Insecure.IsNull() {
+		conf.Insecure = config.Insecure.ValueBool()
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	client, err := client.NewClient(conf)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create watson API client", err.Error())
@@ -157,10 +292,14 @@ func (p *watsonProvider) Configure(ctx context.Context, req provider.ConfigureRe
 
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.FunctionData = client
+	resp.EphemeralResourceData = client
 }
 
 func (p *watsonProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{}
+	return []func() resource.Resource{
+		NewStackResource,
+	}
 }
 
 func (p *watsonProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
@@ -169,6 +308,40 @@ func (p *watsonProvider) DataSources(ctx context.Context) []func() datasource.Da
 	}
 }
 
+func (p *watsonProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewOutputFunction,
+	}
+}
+
+func (p *watsonProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewOutputsEphemeralResource,
+	}
+}
+
+// parseDurationAttribute parses a Go duration string attribute, adding an
+// attribute error diagnostic and returning false if it is set but invalid.
+// A null attribute returns false without any diagnostic, leaving the
+// client's default (or its own environment variable) in effect.
+func parseDurationAttribute(attribute types.String, attributePath path.Path, resp *provider.ConfigureResponse) (time.Duration, bool) {
+	if attribute.IsNull() || attribute.IsUnknown() {
+		return 0, false
+	}
+
+	d, err := time.ParseDuration(attribute.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			attributePath,
+			"Invalid duration",
+			fmt.Sprintf("%q is not a valid duration: %s", attribute.ValueString(), err),
+		)
+		return 0, false
+	}
+
+	return d, true
+}
+
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &watsonProvider{
@@ -5,15 +5,29 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math/big"
 
 	"github.com/Lenstra/watson/internal/client"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+// reservedOutputAttrNames are the top-level schema.Schema attribute names of
+// watson_outputs. A "defaults" key that collides with one of these would be
+// ambiguous once merged into "values", so it is rejected at plan time.
+var reservedOutputAttrNames = map[string]bool{
+	"id":       true,
+	"stack":    true,
+	"outputs":  true,
+	"defaults": true,
+	"values":   true,
+}
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var (
 	_ datasource.DataSource              = &OutputsDataSource{}
@@ -24,6 +38,66 @@ func NewOutputsDataSource() datasource.DataSource {
 	return &OutputsDataSource{}
 }
 
+// outputAttrTypes is the object type shared by the "outputs" map attribute
+// of the data source and of the watson_outputs_ephemeral ephemeral resource.
+// "value" is dynamic because a Watson output can be a string, number, bool,
+// list, or map.
+var outputAttrTypes = map[string]attr.Type{
+	"value":      types.DynamicType,
+	"sensitive":  types.BoolType,
+	"deprecated": types.StringType,
+	"warning":    types.StringType,
+}
+
+// outputValueToAttr recursively converts a decoded JSON output value into
+// its equivalent attr.Value/attr.Type pair. Objects become types.Object,
+// arrays become types.Tuple (their elements need not share a type), and
+// json.Number is parsed into a big.Float with a 64-bit mantissa, so integers
+// up to 2^64 keep every digit; larger integers and decimal fractions are
+// rounded to the nearest 64-bit-mantissa value.
+func outputValueToAttr(v interface{}) (attr.Value, attr.Type, error) {
+	switch val := v.(type) {
+	case nil:
+		return types.StringNull(), types.StringType, nil
+	case bool:
+		return types.BoolValue(val), types.BoolType, nil
+	case string:
+		return types.StringValue(val), types.StringType, nil
+	case json.Number:
+		f, _, err := big.ParseFloat(val.String(), 10, 0, big.ToNearestEven)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse number %q: %w", val, err)
+		}
+		return types.NumberValue(f), types.NumberType, nil
+	case []interface{}:
+		elemValues := make([]attr.Value, len(val))
+		elemTypes := make([]attr.Type, len(val))
+		for i, e := range val {
+			ev, et, err := outputValueToAttr(e)
+			if err != nil {
+				return nil, nil, err
+			}
+			elemValues[i] = ev
+			elemTypes[i] = et
+		}
+		return types.TupleValueMust(elemTypes, elemValues), types.TupleType{ElemTypes: elemTypes}, nil
+	case map[string]interface{}:
+		attrTypes := make(map[string]attr.Type, len(val))
+		attrValues := make(map[string]attr.Value, len(val))
+		for k, e := range val {
+			ev, et, err := outputValueToAttr(e)
+			if err != nil {
+				return nil, nil, err
+			}
+			attrTypes[k] = et
+			attrValues[k] = ev
+		}
+		return types.ObjectValueMust(attrTypes, attrValues), types.ObjectType{AttrTypes: attrTypes}, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported output value type %T", val)
+	}
+}
+
 // OutputsDataSource defines the data source implementation.
 type OutputsDataSource struct {
 	client *client.Client
@@ -31,9 +105,11 @@ type OutputsDataSource struct {
 
 // OutputsDataSourceModel describes the data source data model.
 type OutputsDataSourceModel struct {
-	Id      types.String `tfsdk:"id"`
-	Stack   types.String `tfsdk:"stack"`
-	Outputs types.Map    `tfsdk:"outputs"`
+	Id       types.String  `tfsdk:"id"`
+	Stack    types.String  `tfsdk:"stack"`
+	Outputs  types.Map     `tfsdk:"outputs"`
+	Defaults types.Dynamic `tfsdk:"defaults"`
+	Values   types.Dynamic `tfsdk:"values"`
 }
 
 func (d *OutputsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -58,14 +134,21 @@ func (d *OutputsDataSource) Schema(ctx context.Context, req datasource.SchemaReq
 				MarkdownDescription: "Example configurable attribute.",
 				Computed:            true,
 				ElementType: types.ObjectType{
-					AttrTypes: map[string]attr.Type{
-						"value":      types.StringType,
-						"sensitive":  types.BoolType,
-						"deprecated": types.StringType,
-						"warning":    types.StringType,
-					},
+					AttrTypes: outputAttrTypes,
 				},
 			},
+			"defaults": schema.DynamicAttribute{
+				MarkdownDescription: "An object of default values merged under `values` for outputs the stack " +
+					"does not define, mirroring `terraform_remote_state`'s `defaults` argument. Keys cannot " +
+					"shadow a top-level attribute of this data source.",
+				Optional: true,
+			},
+			"values": schema.DynamicAttribute{
+				MarkdownDescription: "The stack's outputs merged with `defaults` and exposed as top-level " +
+					"attributes, e.g. `data.watson_outputs.foo.values.hostname` instead of " +
+					"`data.watson_outputs.foo.outputs.hostname.value`.",
+				Computed: true,
+			},
 		},
 	}
 }
@@ -103,7 +186,7 @@ func (d *OutputsDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	stack := data.Stack.ValueString()
 	data.Id = types.StringValue(stack)
 
-	outputs, err := d.client.GetOutputs(stack)
+	outputs, err := d.client.GetOutputs(ctx, stack)
 	if err != nil {
 		resp.Diagnostics.AddError(fmt.Sprintf("Failed to read outputs of %q", "stack"), err.Error())
 		return
@@ -114,50 +197,74 @@ func (d *OutputsDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 
+	values := map[string]attr.Value{}
+	if !data.Defaults.IsNull() && !data.Defaults.IsUnknown() {
+		if obj, ok := data.Defaults.UnderlyingValue().(types.Object); ok {
+			for k, v := range obj.Attributes() {
+				if reservedOutputAttrNames[k] {
+					resp.Diagnostics.AddAttributeError(
+						path.Root("defaults"),
+						"Reserved attribute name",
+						fmt.Sprintf("%q is a top-level attribute of watson_outputs and cannot be used as a defaults key", k),
+					)
+					continue
+				}
+				values[k] = v
+			}
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	result := map[string]attr.Value{}
 	for k, v := range *outputs {
-		switch value := v.Value.(type) {
-		case string:
-			result[k] = types.ObjectValueMust(
-				map[string]attr.Type{
-					"value":      types.StringType,
-					"sensitive":  types.BoolType,
-					"deprecated": types.StringType,
-					"warning":    types.StringType,
-				},
-				map[string]attr.Value{
-					"value":      types.StringValue(value),
-					"deprecated": types.StringValue(v.Deprecated),
-					"warning":    types.StringValue(v.Warning),
-					"sensitive":  types.BoolValue(v.Sensitive),
-				},
-			)
-
-		default:
-			resp.Diagnostics.AddWarning("ignored output", fmt.Sprintf("output %q has type %T and is ignored for now", k, value))
+		value, _, err := outputValueToAttr(v.Value)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to decode output %q", k), err.Error())
+			continue
 		}
 
+		result[k] = types.ObjectValueMust(
+			outputAttrTypes,
+			map[string]attr.Value{
+				"value":      types.DynamicValue(value),
+				"deprecated": types.StringValue(v.Deprecated),
+				"warning":    types.StringValue(v.Warning),
+				"sensitive":  types.BoolValue(v.Sensitive),
+			},
+		)
+		values[k] = value
+
 		if v.Deprecated != "" {
 			resp.Diagnostics.AddWarning(fmt.Sprintf("Output %s is deprecated", k), v.Deprecated)
 		}
 
-		if v.Deprecated != "" {
+		if v.Warning != "" {
 			resp.Diagnostics.AddWarning(fmt.Sprintf("The output %s has a warning", k), v.Warning)
 		}
 	}
 
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	data.Outputs = types.MapValueMust(
 		types.ObjectType{
-			AttrTypes: map[string]attr.Type{
-				"value":      types.StringType,
-				"sensitive":  types.BoolType,
-				"deprecated": types.StringType,
-				"warning":    types.StringType,
-			},
+			AttrTypes: outputAttrTypes,
 		},
 		result,
 	)
 
+	valuesAttrTypes := make(map[string]attr.Type, len(values))
+	valuesAttrValues := make(map[string]attr.Value, len(values))
+	for k, v := range values {
+		valuesAttrTypes[k] = types.DynamicType
+		valuesAttrValues[k] = types.DynamicValue(v)
+	}
+	data.Values = types.DynamicValue(types.ObjectValueMust(valuesAttrTypes, valuesAttrValues))
+
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
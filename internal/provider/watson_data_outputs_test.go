@@ -1,9 +1,15 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"math/big"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
@@ -14,7 +20,7 @@ func TestAccOutputsDataSource(t *testing.T) {
 		PreCheck:                 func() { testAccPreCheck(t) },
 		CheckDestroy: func(s *terraform.State) error {
 			// Now check that the dependency has been saved
-			stack, err := testClient.GetStack("backend/load-balancers")
+			stack, err := testClient.GetStack(context.Background(), "backend/load-balancers")
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -57,6 +63,78 @@ func TestAccOutputsDataSource(t *testing.T) {
 					resource.TestCheckResourceAttr("data.watson_outputs.test", "outputs.hostname.warning", ""),
 				),
 			},
+			{
+				Config: providerConfig + `data "watson_outputs" "test" {
+					stack = "backend/load-balancers"
+					defaults = {
+						hostname = "localhost"
+						port     = 443
+					}
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.watson_outputs.test", "values.hostname", "https://hello.eu-central-1.blabla"),
+					resource.TestCheckResourceAttr("data.watson_outputs.test", "values.port", "443"),
+				),
+			},
+			{
+				Config: providerConfig + `data "watson_outputs" "test" {
+					stack = "backend/load-balancers"
+					defaults = {
+						outputs = "not allowed"
+					}
+				}`,
+				ExpectError: regexp.MustCompile(`Reserved attribute name`),
+			},
 		},
 	})
 }
+
+func TestOutputValueToAttr(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want attr.Value
+	}{
+		{"string", `"hello"`, types.StringValue("hello")},
+		{"bool", `true`, types.BoolValue(true)},
+		{"null", `null`, types.StringNull()},
+		{"integer", `42`, types.NumberValue(big.NewFloat(42))},
+		{"float", `4.2`, types.NumberValue(func() *big.Float { f, _ := new(big.Float).SetPrec(64).SetString("4.2"); return f }())},
+		{
+			"list",
+			`["a", 1, true]`,
+			types.TupleValueMust(
+				[]attr.Type{types.StringType, types.NumberType, types.BoolType},
+				[]attr.Value{types.StringValue("a"), types.NumberValue(big.NewFloat(1)), types.BoolValue(true)},
+			),
+		},
+		{
+			"map",
+			`{"a": "b"}`,
+			types.ObjectValueMust(
+				map[string]attr.Type{"a": types.StringType},
+				map[string]attr.Value{"a": types.StringValue("b")},
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := json.NewDecoder(strings.NewReader(tt.in))
+			dec.UseNumber()
+
+			var v interface{}
+			if err := dec.Decode(&v); err != nil {
+				t.Fatal(err)
+			}
+
+			got, _, err := outputValueToAttr(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("outputValueToAttr(%s) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Lenstra/watson/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// outputsRenewInterval is how long after Open (or the last Renew) Terraform
+// waits before calling Renew on a watson_outputs_ephemeral value. Renew only
+// moves this deadline forward; it does not fetch the outputs again.
+const outputsRenewInterval = 15 * time.Minute
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ ephemeral.EphemeralResource              = &OutputsEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &OutputsEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithRenew     = &OutputsEphemeralResource{}
+)
+
+func NewOutputsEphemeralResource() ephemeral.EphemeralResource {
+	return &OutputsEphemeralResource{}
+}
+
+// OutputsEphemeralResource is the ephemeral equivalent of OutputsDataSource:
+// it fetches a stack's outputs at plan/apply time only, so sensitive values
+// never get written to state or a saved plan.
+type OutputsEphemeralResource struct {
+	client *client.Client
+}
+
+// OutputsEphemeralResourceModel describes the ephemeral resource data model.
+type OutputsEphemeralResourceModel struct {
+	Stack   types.String `tfsdk:"stack"`
+	Outputs types.Map    `tfsdk:"outputs"`
+}
+
+func (e *OutputsEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_outputs_ephemeral"
+}
+
+func (e *OutputsEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches a stack's outputs without persisting them to state or a saved plan, " +
+			"making it suitable for sensitive outputs. See `watson_outputs` for the state-backed equivalent.",
+
+		Attributes: map[string]schema.Attribute{
+			"stack": schema.StringAttribute{
+				MarkdownDescription: "The stack to get the outputs of.",
+				Required:            true,
+			},
+			"outputs": schema.MapAttribute{
+				MarkdownDescription: "The stack's outputs, keyed by output name.",
+				Computed:            true,
+				ElementType: types.ObjectType{
+					AttrTypes: outputAttrTypes,
+				},
+			},
+		},
+	}
+}
+
+func (e *OutputsEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected EphemeralResource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	e.client = client
+}
+
+func (e *OutputsEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data OutputsEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	e.readOutputs(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+	resp.RenewAt = time.Now().Add(outputsRenewInterval)
+}
+
+// Renew extends how long the value resolved by Open is trusted for. Watson
+// outputs have no server-side expiry of their own, so there is nothing to
+// re-fetch here; Renew exists so a long apply doesn't treat the value as
+// stale before it finishes.
+func (e *OutputsEphemeralResource) Renew(ctx context.Context, req ephemeral.RenewRequest, resp *ephemeral.RenewResponse) {
+	resp.RenewAt = time.Now().Add(outputsRenewInterval)
+}
+
+// readOutputs fetches and decodes the outputs of data.Stack into
+// data.Outputs, appending any failures to diags.
+func (e *OutputsEphemeralResource) readOutputs(ctx context.Context, data *OutputsEphemeralResourceModel, diags *diag.Diagnostics) {
+	stack := data.Stack.ValueString()
+
+	outputs, err := e.client.GetOutputs(ctx, stack)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to read outputs of %q", stack), err.Error())
+		return
+	}
+
+	if outputs == nil {
+		diags.AddError("Unknown stack", fmt.Sprintf("No stack named %q could be found", stack))
+		return
+	}
+
+	result := map[string]attr.Value{}
+	for k, v := range *outputs {
+		value, _, err := outputValueToAttr(v.Value)
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Failed to decode output %q", k), err.Error())
+			continue
+		}
+
+		result[k] = types.ObjectValueMust(
+			outputAttrTypes,
+			map[string]attr.Value{
+				"value":      types.DynamicValue(value),
+				"deprecated": types.StringValue(v.Deprecated),
+				"warning":    types.StringValue(v.Warning),
+				"sensitive":  types.BoolValue(v.Sensitive),
+			},
+		)
+	}
+
+	data.Outputs = types.MapValueMust(
+		types.ObjectType{
+			AttrTypes: outputAttrTypes,
+		},
+		result,
+	)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccOutputsEphemeralResource exercises watson_outputs_ephemeral. It
+// requires a Terraform CLI with ephemeral resource support (>= 1.10); skip
+// rather than fail on older binaries so the rest of the suite still runs.
+func TestAccOutputsEphemeralResource(t *testing.T) {
+	if v := os.Getenv("TF_ACC_TERRAFORM_VERSION"); v != "" && tfVersionLess(v, "1.10.0") {
+		t.Skip("ephemeral resources require Terraform >= 1.10")
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `ephemeral "watson_outputs_ephemeral" "test" {
+					stack = "hello"
+				}`,
+				ExpectError: regexp.MustCompile(`"hello" is not a valid stack name`),
+			},
+			{
+				// watson_outputs_ephemeral never reaches state, so there is
+				// nothing to assert with resource.TestCheckResourceAttr
+				// here; a successful, error-free apply is the test.
+				Config: providerConfig + `ephemeral "watson_outputs_ephemeral" "test" {
+					stack = "backend/load-balancers"
+				}`,
+			},
+		},
+	})
+}
+
+// tfVersionLess reports whether version a is numerically less than b,
+// comparing "major.minor.patch"-style dotted versions component by
+// component rather than lexicographically, so "1.9.0" < "1.10.0" compares
+// correctly. Any non-numeric component (e.g. a "-dev" suffix) is treated as
+// 0 for that position.
+func tfVersionLess(a, b string) bool {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var av, bv int
+		if i < len(as) {
+			av, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			bv, _ = strconv.Atoi(bs[i])
+		}
+		if av != bv {
+			return av < bv
+		}
+	}
+
+	return false
+}
+
+func TestTfVersionLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1.9.0", "1.10.0", true},
+		{"1.10.0", "1.9.0", false},
+		{"1.10.0", "1.10.0", false},
+		{"1.10.1", "1.10.0", false},
+		{"1.9.5", "1.10.0", true},
+		{"2.0.0", "1.10.0", false},
+	}
+
+	for _, tt := range tests {
+		if got := tfVersionLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("tfVersionLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
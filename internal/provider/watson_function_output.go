@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Lenstra/watson/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure OutputFunction fully satisfies framework interfaces.
+var (
+	_ function.Function              = &OutputFunction{}
+	_ function.FunctionWithConfigure = &OutputFunction{}
+)
+
+func NewOutputFunction() function.Function {
+	return &OutputFunction{}
+}
+
+// OutputFunction implements provider::watson::output(stack, name), a
+// one-shot alternative to declaring a data "watson_outputs" block. The
+// framework instantiates a fresh OutputFunction per call, so any caching of
+// results across calls has to live on the shared *client.Client instead of
+// here; see client.Client.GetOutputsCached.
+type OutputFunction struct {
+	client *client.Client
+}
+
+func (f *OutputFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "output"
+}
+
+func (f *OutputFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Look up a single stack output",
+		Description: "Resolves a single output of a stack without requiring a data \"watson_outputs\" block.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "stack",
+				Description: "The stack to read the output of, e.g. \"backend/load-balancers\".",
+			},
+			function.StringParameter{
+				Name:        "name",
+				Description: "The name of the output to resolve.",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *OutputFunction) Configure(ctx context.Context, req function.ConfigureRequest, resp *function.ConfigureResponse) {
+	if req.FunctionData == nil {
+		return
+	}
+
+	client, ok := req.FunctionData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Function Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.FunctionData),
+		)
+		return
+	}
+
+	f.client = client
+}
+
+func (f *OutputFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var stack, name string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &stack, &name))
+	if resp.Error != nil {
+		return
+	}
+
+	outputs, err := f.client.GetOutputsCached(ctx, stack)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	if outputs == nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, fmt.Sprintf("no stack named %q could be found", stack)))
+		return
+	}
+
+	output, ok := (*outputs)[name]
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(1, fmt.Sprintf("no output named %q in stack %q", name, stack)))
+		return
+	}
+
+	value, _, err := outputValueToAttr(output.Value)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewFuncError(err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, types.DynamicValue(value)))
+}
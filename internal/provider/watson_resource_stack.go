@@ -0,0 +1,449 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Lenstra/watson/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &StackResource{}
+	_ resource.ResourceWithConfigure   = &StackResource{}
+	_ resource.ResourceWithImportState = &StackResource{}
+)
+
+func NewStackResource() resource.Resource {
+	return &StackResource{}
+}
+
+// StackResource manages a Watson stack and the outputs it publishes.
+type StackResource struct {
+	client *client.Client
+}
+
+// stackOutputModel describes one entry of the "outputs" map attribute.
+type stackOutputModel struct {
+	Value      types.Dynamic `tfsdk:"value"`
+	Sensitive  types.Bool    `tfsdk:"sensitive"`
+	Deprecated types.String  `tfsdk:"deprecated"`
+	Warning    types.String  `tfsdk:"warning"`
+	Type       types.String  `tfsdk:"type"`
+}
+
+// StackResourceModel describes the resource data model.
+type StackResourceModel struct {
+	Id        types.String                `tfsdk:"id"`
+	Namespace types.String                `tfsdk:"namespace"`
+	Name      types.String                `tfsdk:"name"`
+	Outputs   map[string]stackOutputModel `tfsdk:"outputs"`
+}
+
+func (r *StackResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stack"
+}
+
+func (r *StackResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Creates a Watson stack and publishes its outputs.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The stack id, `namespace/name`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				MarkdownDescription: "The stack's namespace.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The stack's name.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"outputs": schema.MapNestedAttribute{
+				MarkdownDescription: "The outputs published by this stack, keyed by output name.",
+				Optional:            true,
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"value": schema.DynamicAttribute{
+							MarkdownDescription: "The output's value.",
+							Required:            true,
+						},
+						"sensitive": schema.BoolAttribute{
+							MarkdownDescription: "Whether the output should be hidden from logs. Defaults to false.",
+							Optional:            true,
+							Computed:            true,
+							PlanModifiers: []planmodifier.Bool{
+								boolplanmodifier.UseStateForUnknown(),
+							},
+						},
+						"deprecated": schema.StringAttribute{
+							MarkdownDescription: "A deprecation message shown to consumers of this output.",
+							Optional:            true,
+						},
+						"warning": schema.StringAttribute{
+							MarkdownDescription: "A warning message shown to consumers of this output.",
+							Optional:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "The output's JSON type (`string`, `number`, `bool`, `list`, `map`, or `null`).",
+							Computed:            true,
+							PlanModifiers: []planmodifier.String{
+								stringplanmodifier.UseStateForUnknown(),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *StackResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *StackResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data StackResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := data.Namespace.ValueString()
+	name := data.Name.ValueString()
+
+	inputs, diags := stackOutputInputs(data.Outputs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if _, err := r.client.CreateStack(ctx, namespace, name, inputs); err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to create stack %q", fmt.Sprintf("%s/%s", namespace, name)), err.Error())
+		return
+	}
+
+	data.Id = types.StringValue(fmt.Sprintf("%s/%s", namespace, name))
+	resp.Diagnostics.Append(resolveOutputs(data.Outputs)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StackResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data StackResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace, name, err := splitStackId(data.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid stack id", err.Error())
+		return
+	}
+
+	outputs, err := r.client.GetOutputs(ctx, fmt.Sprintf("%s/%s", namespace, name))
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to read outputs of %q", data.Id.ValueString()), err.Error())
+		return
+	}
+	if outputs == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.Namespace = types.StringValue(namespace)
+	data.Name = types.StringValue(name)
+
+	result := map[string]stackOutputModel{}
+	for k, v := range *outputs {
+		value, _, err := outputValueToAttr(v.Value)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to decode output %q", k), err.Error())
+			continue
+		}
+
+		result[k] = stackOutputModel{
+			Value:      types.DynamicValue(value),
+			Sensitive:  types.BoolValue(v.Sensitive),
+			Deprecated: types.StringValue(v.Deprecated),
+			Warning:    types.StringValue(v.Warning),
+			Type:       types.StringValue(jsonValueTypeName(v.Value)),
+		}
+	}
+	data.Outputs = result
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StackResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state StackResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := data.Namespace.ValueString()
+	name := data.Name.ValueString()
+
+	inputs, diags := stackOutputInputs(data.Outputs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	stack, err := r.client.UpdateStack(ctx, namespace, name, inputs)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to update stack %q", fmt.Sprintf("%s/%s", namespace, name)), err.Error())
+		return
+	}
+
+	warnAboutRemovedOutputs(resp.Diagnostics.AddWarning, state.Outputs, data.Outputs, stack)
+
+	data.Id = types.StringValue(fmt.Sprintf("%s/%s", namespace, name))
+	resp.Diagnostics.Append(resolveOutputs(data.Outputs)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StackResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data StackResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := data.Namespace.ValueString()
+	name := data.Name.ValueString()
+
+	if err := r.client.DeleteStack(ctx, namespace, name); err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to delete stack %q", fmt.Sprintf("%s/%s", namespace, name)), err.Error())
+	}
+}
+
+func (r *StackResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if _, _, err := splitStackId(req.ID); err != nil {
+		resp.Diagnostics.AddError("Invalid import id", err.Error())
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// splitStackId splits a stack id of the form "namespace/name" into its two
+// parts.
+func splitStackId(id string) (namespace, name string, err error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("%q is not a valid stack id, expected \"namespace/name\"", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// stackOutputInputs converts the resource's "outputs" model into the
+// payload sent to the Watson API.
+func stackOutputInputs(outputs map[string]stackOutputModel) (map[string]client.StackOutputInput, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	inputs := make(map[string]client.StackOutputInput, len(outputs))
+	for k, v := range outputs {
+		value, err := attrToRawValue(v.Value)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("outputs").AtMapKey(k).AtName("value"),
+				fmt.Sprintf("Failed to encode output %q", k),
+				err.Error(),
+			)
+			continue
+		}
+
+		inputs[k] = client.StackOutputInput{
+			Value:      value,
+			Sensitive:  v.Sensitive.ValueBool(),
+			Deprecated: v.Deprecated.ValueString(),
+			Warning:    v.Warning.ValueString(),
+		}
+	}
+
+	return inputs, diags
+}
+
+// resolveOutputs fills in the computed attributes of each output after a
+// create/update: "sensitive" defaults to false when the user left it
+// null/unknown, and "type" is derived from the value the user just set.
+// Both must be resolved to a known value before resp.State.Set, or
+// Terraform rejects the apply as inconsistent.
+func resolveOutputs(outputs map[string]stackOutputModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for k, v := range outputs {
+		value, err := attrToRawValue(v.Value)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("outputs").AtMapKey(k).AtName("value"),
+				fmt.Sprintf("Failed to encode output %q", k),
+				err.Error(),
+			)
+			continue
+		}
+		if v.Sensitive.IsNull() || v.Sensitive.IsUnknown() {
+			v.Sensitive = types.BoolValue(false)
+		}
+		v.Type = types.StringValue(jsonValueTypeName(value))
+		outputs[k] = v
+	}
+
+	return diags
+}
+
+// warnAboutRemovedOutputs emits a diagnostic warning through addWarning for
+// every output that the plan removes or newly deprecates while stack still
+// has consumers, since those consumers may break.
+func warnAboutRemovedOutputs(addWarning func(summary, detail string), before, after map[string]stackOutputModel, stack *client.Stack) {
+	if stack == nil || len(stack.UsedBy) == 0 {
+		return
+	}
+
+	consumers := make([]string, 0, len(stack.UsedBy))
+	for _, c := range stack.UsedBy {
+		consumers = append(consumers, c.Id)
+	}
+
+	for k, prev := range before {
+		next, stillDeclared := after[k]
+		switch {
+		case !stillDeclared:
+			addWarning(
+				fmt.Sprintf("Output %s is still in use", k),
+				fmt.Sprintf("Output %q was removed but is still consumed by: %s", k, strings.Join(consumers, ", ")),
+			)
+		case next.Deprecated.ValueString() != "" && prev.Deprecated.ValueString() == "":
+			addWarning(
+				fmt.Sprintf("Output %s is still in use", k),
+				fmt.Sprintf("Output %q was deprecated but is still consumed by: %s", k, strings.Join(consumers, ", ")),
+			)
+		}
+	}
+}
+
+// jsonValueTypeName names the JSON shape of a decoded output value, for use
+// in the computed "type" attribute.
+func jsonValueTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "map"
+	default:
+		return "unknown"
+	}
+}
+
+// attrToRawValue is the inverse of outputValueToAttr: it converts a
+// (possibly dynamic) attr.Value back into a plain Go value suitable for
+// json.Marshal.
+func attrToRawValue(v attr.Value) (interface{}, error) {
+	switch val := v.(type) {
+	case types.Dynamic:
+		if val.IsNull() || val.IsUnderlyingValueNull() {
+			return nil, nil
+		}
+		return attrToRawValue(val.UnderlyingValue())
+	case types.String:
+		if val.IsNull() {
+			return nil, nil
+		}
+		return val.ValueString(), nil
+	case types.Bool:
+		if val.IsNull() {
+			return nil, nil
+		}
+		return val.ValueBool(), nil
+	case types.Number:
+		if val.IsNull() {
+			return nil, nil
+		}
+		return json.Number(val.ValueBigFloat().Text('f', -1)), nil
+	case types.Tuple:
+		elems := val.Elements()
+		out := make([]interface{}, len(elems))
+		for i, e := range elems {
+			rv, err := attrToRawValue(e)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rv
+		}
+		return out, nil
+	case types.List:
+		elems := val.Elements()
+		out := make([]interface{}, len(elems))
+		for i, e := range elems {
+			rv, err := attrToRawValue(e)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rv
+		}
+		return out, nil
+	case types.Object:
+		attrs := val.Attributes()
+		out := make(map[string]interface{}, len(attrs))
+		for k, e := range attrs {
+			rv, err := attrToRawValue(e)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = rv
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unsupported output value type %T", val)
+	}
+}
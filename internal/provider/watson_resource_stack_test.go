@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Lenstra/watson/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccStackResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		CheckDestroy: func(s *terraform.State) error {
+			stack, err := testClient.GetStack(context.Background(), "acctest/stack")
+			if err != nil {
+				return err
+			}
+			if stack != nil {
+				return fmt.Errorf("stack acctest/stack still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `resource "watson_stack" "test" {
+					namespace = "acctest"
+					name      = "stack"
+
+					outputs = {
+						hostname = {
+							value = "hello.example.com"
+						}
+					}
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("watson_stack.test", "id", "acctest/stack"),
+					resource.TestCheckResourceAttr("watson_stack.test", "outputs.hostname.value", "hello.example.com"),
+					resource.TestCheckResourceAttr("watson_stack.test", "outputs.hostname.type", "string"),
+					resource.TestCheckResourceAttr("watson_stack.test", "outputs.hostname.sensitive", "false"),
+				),
+			},
+			{
+				Config: providerConfig + `resource "watson_stack" "test" {
+					namespace = "acctest"
+					name      = "stack"
+
+					outputs = {
+						hostname = {
+							value     = "hello.example.com"
+							sensitive = true
+						}
+						port = {
+							value = 443
+						}
+					}
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("watson_stack.test", "outputs.hostname.sensitive", "true"),
+					resource.TestCheckResourceAttr("watson_stack.test", "outputs.port.value", "443"),
+					resource.TestCheckResourceAttr("watson_stack.test", "outputs.port.type", "number"),
+				),
+			},
+			{
+				ResourceName:      "watson_stack.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestWarnAboutRemovedOutputs(t *testing.T) {
+	stackWithConsumers := &client.Stack{
+		Id: "acctest/stack",
+		UsedBy: []struct {
+			Id         string    `json:"id"`
+			URL        string    `json:"url"`
+			LastUsedAt time.Time `json:"last_used_at"`
+		}{
+			{Id: "acctest/consumer"},
+		},
+	}
+
+	hostname := stackOutputModel{Value: types.DynamicValue(types.StringValue("hello.example.com"))}
+	deprecated := hostname
+	deprecated.Deprecated = types.StringValue("use fqdn instead")
+
+	tests := []struct {
+		name   string
+		before map[string]stackOutputModel
+		after  map[string]stackOutputModel
+		stack  *client.Stack
+		want   bool
+	}{
+		{
+			name:   "removed output with consumers warns",
+			before: map[string]stackOutputModel{"hostname": hostname},
+			after:  map[string]stackOutputModel{},
+			stack:  stackWithConsumers,
+			want:   true,
+		},
+		{
+			name:   "newly deprecated output with consumers warns",
+			before: map[string]stackOutputModel{"hostname": hostname},
+			after:  map[string]stackOutputModel{"hostname": deprecated},
+			stack:  stackWithConsumers,
+			want:   true,
+		},
+		{
+			name:   "no consumers never warns",
+			before: map[string]stackOutputModel{"hostname": hostname},
+			after:  map[string]stackOutputModel{},
+			stack:  &client.Stack{Id: "acctest/stack"},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warned bool
+			warnAboutRemovedOutputs(func(summary, detail string) {
+				warned = true
+			}, tt.before, tt.after, tt.stack)
+
+			if warned != tt.want {
+				t.Fatalf("warnAboutRemovedOutputs() warned = %v, want %v", warned, tt.want)
+			}
+		})
+	}
+}